@@ -0,0 +1,362 @@
+package cost
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/brittandeyoung/ckia/internal/aws/cost/pricing"
+	"github.com/brittandeyoung/ckia/internal/client"
+	"github.com/brittandeyoung/ckia/internal/common"
+)
+
+const (
+	IdleAuroraClustersCheckId                  = "ckia:aws:cost:IdleAuroraClusters"
+	IdleAuroraClustersCheckName                = "RDS Idle Aurora Clusters"
+	IdleAuroraClustersCheckDescription         = "Checks your Amazon Aurora clusters for any that appear to be idle. Aurora reports connections against the cluster endpoint and its writer/reader instances rather than a single DB instance, so idle clusters are not caught by the RDS idle DB instances check. For Aurora Serverless, a cluster sitting at its minimum capacity with no connections is also considered idle."
+	IdleAuroraClustersCheckCriteria            = "Any Aurora cluster that has not had a connection in the last 7 days, or whose Aurora Serverless capacity has stayed at or below the configured floor for that long, is considered idle."
+	IdleAuroraClustersCheckRecommendedAction   = "Consider taking a final snapshot of the idle cluster and then either deleting it, or for Aurora Serverless clusters, confirming the minimum capacity setting is as low as the workload allows. Clusters kept alive only for occasional access can often be scaled to zero or deleted and restored from snapshot when needed."
+	IdleAuroraClustersCheckAdditionalResources = "See comparable AWS Trusted advisor check: https://docs.aws.amazon.com/awssupport/latest/user/cost-optimization-checks.html#amazon-rds-idle-dbs-instances"
+)
+
+type IdleAuroraCluster struct {
+	Region      string `json:"region"`
+	ClusterArn  string `json:"clusterArn"`
+	EngineMode  string `json:"engineMode"`
+	WriterCount int    `json:"writerCount"`
+	ReaderCount int    `json:"readerCount"`
+	// StorageProvisionedInGB is actually consumed storage (CloudWatch
+	// VolumeBytesUsed), not a provisioned size - Aurora storage auto-scales
+	// and has no fixed allocation the way a standard RDS instance does.
+	StorageProvisionedInGB  int `json:"storageProvisionedInGB"`
+	DaysSinceLastConnection int `json:"daysSinceLastConnection"`
+	EstimatedMonthlySavings int `json:"estimatedMonthlySavings"`
+}
+
+type IdleAuroraClustersCheck struct {
+	common.Check
+	IdleAuroraClusters []IdleAuroraCluster `json:"idleAuroraClusters"`
+}
+
+// IdleAuroraClustersConfig controls how aggressively IdleAuroraClustersCheck
+// flags an Aurora cluster as idle. Zero-valued fields fall back to
+// DefaultIdleAuroraClustersConfig.
+type IdleAuroraClustersConfig struct {
+	// IdleThresholdDays is how long a cluster can go without a connection
+	// before it is reported as idle.
+	IdleThresholdDays int32 `json:"idleThresholdDays,omitempty"`
+	// LookbackDays is how far back CloudWatch metrics are queried.
+	LookbackDays int32 `json:"lookbackDays,omitempty"`
+	// MinAverageConnections lets a cluster with a trickle of connections
+	// still be treated as idle.
+	MinAverageConnections float64 `json:"minAverageConnections,omitempty"`
+	// MinAverageACU is the Aurora Serverless capacity floor, in ACUs, at or
+	// below which a cluster is treated as idle regardless of connection count.
+	MinAverageACU float64 `json:"minAverageACU,omitempty"`
+	// MetricPeriodSeconds is the CloudWatch statistic period used when
+	// querying DatabaseConnections and ServerlessDatabaseCapacity.
+	MetricPeriodSeconds int32 `json:"metricPeriodSeconds,omitempty"`
+}
+
+// DefaultIdleAuroraClustersConfig mirrors DefaultIdleDBInstancesConfig's
+// defaults, with an ACU floor tuned for Aurora Serverless v2's minimum of
+// 0.5 ACU.
+func DefaultIdleAuroraClustersConfig() IdleAuroraClustersConfig {
+	return IdleAuroraClustersConfig{
+		IdleThresholdDays:     7,
+		LookbackDays:          14,
+		MinAverageConnections: 0,
+		MinAverageACU:         0.5,
+		MetricPeriodSeconds:   3600,
+	}
+}
+
+func (c IdleAuroraClustersConfig) withDefaults() IdleAuroraClustersConfig {
+	defaults := DefaultIdleAuroraClustersConfig()
+	if c.IdleThresholdDays == 0 {
+		c.IdleThresholdDays = defaults.IdleThresholdDays
+	}
+	if c.LookbackDays == 0 {
+		c.LookbackDays = defaults.LookbackDays
+	}
+	if c.MinAverageACU == 0 {
+		c.MinAverageACU = defaults.MinAverageACU
+	}
+	if c.MetricPeriodSeconds == 0 {
+		c.MetricPeriodSeconds = defaults.MetricPeriodSeconds
+	}
+	return c
+}
+
+func (v IdleAuroraClustersCheck) List(config IdleAuroraClustersConfig) *IdleAuroraClustersCheck {
+	check := &IdleAuroraClustersCheck{
+		Check: common.Check{
+			Id:                  IdleAuroraClustersCheckId,
+			Name:                IdleAuroraClustersCheckName,
+			Description:         IdleAuroraClustersCheckDescription,
+			Criteria:            IdleAuroraClustersCheckCriteria,
+			RecommendedAction:   IdleAuroraClustersCheckRecommendedAction,
+			AdditionalResources: IdleAuroraClustersCheckAdditionalResources,
+			Config:              config.withDefaults(),
+		},
+	}
+	return check
+}
+
+// RunMultiRegion fans IdleAuroraClustersCheck.Run out across regions using
+// common.MultiRegionRunner, merging every region's idle clusters into a
+// single check result.
+func (v IdleAuroraClustersCheck) RunMultiRegion(ctx context.Context, cfg common.MultiRegionRunnerConfig, newClient common.ClientFactory, config IdleAuroraClustersConfig) (*IdleAuroraClustersCheck, error) {
+	check := new(IdleAuroraClustersCheck).List(config)
+
+	rows, err := common.MultiRegionRunner(ctx, cfg, newClient, func(ctx context.Context, conn client.AWSClient) ([]IdleAuroraCluster, error) {
+		result, err := new(IdleAuroraClustersCheck).Run(ctx, conn, config)
+		if err != nil || result == nil {
+			return nil, err
+		}
+		return result.IdleAuroraClusters, nil
+	})
+
+	check.IdleAuroraClusters = rows
+	return check, err
+}
+
+func (v IdleAuroraClustersCheck) Run(ctx context.Context, conn client.AWSClient, config IdleAuroraClustersConfig) (*IdleAuroraClustersCheck, error) {
+	config = config.withDefaults()
+	check := new(IdleAuroraClustersCheck).List(config)
+
+	currentTime := time.Now()
+
+	in := &rds.DescribeDBClustersInput{}
+	var dbClusters []rdsTypes.DBCluster
+
+	paginator := rds.NewDescribeDBClustersPaginator(conn.RDS, in, func(o *rds.DescribeDBClustersPaginatorOptions) {})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		dbClusters = append(dbClusters, output.DBClusters...)
+	}
+
+	if len(dbClusters) == 0 {
+		return nil, nil
+	}
+
+	var idleAuroraClusters []IdleAuroraCluster
+	for _, dbCluster := range dbClusters {
+
+		connectionMetrics, err := conn.Cloudwatch.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+			MetricName: aws.String("DatabaseConnections"),
+			Period:     aws.Int32(config.MetricPeriodSeconds),
+			Namespace:  aws.String("AWS/RDS"),
+			Statistics: []types.Statistic{types.StatisticAverage},
+			Dimensions: []types.Dimension{
+				{
+					Name:  aws.String("DBClusterIdentifier"),
+					Value: dbCluster.DBClusterIdentifier,
+				},
+			},
+			StartTime: aws.Time(currentTime.AddDate(0, 0, -int(config.LookbackDays))),
+			EndTime:   aws.Time(currentTime),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		daysSinceConnection, connectionFound := expandConnections(connectionMetrics.Datapoints, IdleDBInstancesConfig{
+			IdleThresholdDays:     config.IdleThresholdDays,
+			LookbackDays:          config.LookbackDays,
+			MinAverageConnections: config.MinAverageConnections,
+		})
+
+		engineMode := aws.ToString(dbCluster.EngineMode)
+		// idle is true if the cluster has had no connection within the idle
+		// threshold OR (for Aurora Serverless) its capacity stayed at or below
+		// the configured ACU floor - either signal is sufficient, so a
+		// serverless cluster that is already idle by connections must not be
+		// un-flagged just because its ACU usage happened to sit above the
+		// floor (e.g. a cool-down tail from an earlier job).
+		idle := !connectionFound
+
+		if !idle && isServerless(dbCluster) {
+			idle, err = isServerlessCapacityIdle(ctx, conn, dbCluster, config, currentTime)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if idle {
+			writerCount, readerCount := countClusterMembers(dbCluster.DBClusterMembers)
+
+			storageGB, err := auroraStorageGB(ctx, conn, dbCluster, config, currentTime)
+			if err != nil {
+				return nil, err
+			}
+
+			idleAuroraCluster := IdleAuroraCluster{
+				Region:                  conn.Region,
+				ClusterArn:              aws.ToString(dbCluster.DBClusterArn),
+				EngineMode:              engineMode,
+				WriterCount:             writerCount,
+				ReaderCount:             readerCount,
+				StorageProvisionedInGB:  int(storageGB),
+				DaysSinceLastConnection: daysSinceConnection,
+			}
+
+			savings, err := estimateAuroraMonthlySavings(ctx, conn, dbCluster, storageGB)
+			if err != nil {
+				return nil, err
+			}
+			idleAuroraCluster.EstimatedMonthlySavings = savings
+
+			idleAuroraClusters = append(idleAuroraClusters, idleAuroraCluster)
+		}
+	}
+
+	check.IdleAuroraClusters = idleAuroraClusters
+	return check, nil
+}
+
+// isServerless reports whether dbCluster is Aurora Serverless v1 (engine
+// mode "serverless") or v2 (a provisioned cluster with a serverless v2
+// scaling configuration).
+func isServerless(dbCluster rdsTypes.DBCluster) bool {
+	return aws.ToString(dbCluster.EngineMode) == "serverless" || dbCluster.ServerlessV2ScalingConfiguration != nil
+}
+
+// countClusterMembers tallies dbCluster's members into writer and reader
+// counts. It is a pure function of its inputs so the counting logic can be
+// unit tested without DescribeDBClusters.
+func countClusterMembers(members []rdsTypes.DBClusterMember) (writerCount, readerCount int) {
+	for _, member := range members {
+		if aws.ToBool(member.IsClusterWriter) {
+			writerCount++
+		} else {
+			readerCount++
+		}
+	}
+	return writerCount, readerCount
+}
+
+// isServerlessCapacityIdle treats an Aurora Serverless cluster as idle when
+// its average ServerlessDatabaseCapacity (ACUs) stayed at or below
+// config.MinAverageACU for the full lookback window.
+func isServerlessCapacityIdle(ctx context.Context, conn client.AWSClient, dbCluster rdsTypes.DBCluster, config IdleAuroraClustersConfig, currentTime time.Time) (bool, error) {
+	metrics, err := conn.Cloudwatch.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		MetricName: aws.String("ServerlessDatabaseCapacity"),
+		Period:     aws.Int32(config.MetricPeriodSeconds),
+		Namespace:  aws.String("AWS/RDS"),
+		Statistics: []types.Statistic{types.StatisticAverage},
+		Dimensions: []types.Dimension{
+			{
+				Name:  aws.String("DBClusterIdentifier"),
+				Value: dbCluster.DBClusterIdentifier,
+			},
+		},
+		StartTime: aws.Time(currentTime.AddDate(0, 0, -int(config.LookbackDays))),
+		EndTime:   aws.Time(currentTime),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return acuFloorIdle(metrics.Datapoints, config.MinAverageACU), nil
+}
+
+// acuFloorIdle reports whether every datapoint's average ACU usage stayed at
+// or below minAverageACU, i.e. the cluster never rose above its configured
+// Serverless capacity floor. It is a pure function of its inputs so the
+// floor comparison can be unit tested without CloudWatch.
+func acuFloorIdle(dataPoints []types.Datapoint, minAverageACU float64) bool {
+	for _, dataPoint := range dataPoints {
+		if aws.ToFloat64(dataPoint.Average) > minAverageACU {
+			return false
+		}
+	}
+	return true
+}
+
+// auroraStorageGB returns the cluster's actual consumed storage in GB, from
+// the average of CloudWatch's VolumeBytesUsed over the configured lookback.
+// Aurora bills storage based on usage, not a fixed allocation, and
+// DescribeDBClusters' AllocatedStorage field is a placeholder for Aurora
+// (commonly 1), not the real figure.
+func auroraStorageGB(ctx context.Context, conn client.AWSClient, dbCluster rdsTypes.DBCluster, config IdleAuroraClustersConfig, currentTime time.Time) (float64, error) {
+	metrics, err := conn.Cloudwatch.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		MetricName: aws.String("VolumeBytesUsed"),
+		Period:     aws.Int32(config.MetricPeriodSeconds),
+		Namespace:  aws.String("AWS/RDS"),
+		Statistics: []types.Statistic{types.StatisticAverage},
+		Dimensions: []types.Dimension{
+			{
+				Name:  aws.String("DBClusterIdentifier"),
+				Value: dbCluster.DBClusterIdentifier,
+			},
+		},
+		StartTime: aws.Time(currentTime.AddDate(0, 0, -int(config.LookbackDays))),
+		EndTime:   aws.Time(currentTime),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(metrics.Datapoints) == 0 {
+		return 0, nil
+	}
+
+	var sumBytes float64
+	for _, dataPoint := range metrics.Datapoints {
+		sumBytes += aws.ToFloat64(dataPoint.Average)
+	}
+	averageBytes := sumBytes / float64(len(metrics.Datapoints))
+
+	return averageBytes / bytesPerGiB, nil
+}
+
+// estimateAuroraMonthlySavings approximates the monthly cost of an idle
+// cluster as the compute cost of its member instances plus its Aurora
+// storage. Any price the pricer cannot find is treated as zero.
+func estimateAuroraMonthlySavings(ctx context.Context, conn client.AWSClient, dbCluster rdsTypes.DBCluster, storageGB float64) (int, error) {
+	var total float64
+
+	for _, member := range dbCluster.DBClusterMembers {
+		memberInstance, err := conn.RDS.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: member.DBInstanceIdentifier,
+		})
+		if err != nil {
+			return 0, err
+		}
+		if len(memberInstance.DBInstances) == 0 {
+			continue
+		}
+
+		hourly, err := conn.Pricer.RDSInstancePrice(ctx, pricing.RDSInstancePriceInput{
+			Region:        conn.Region,
+			InstanceClass: aws.ToString(memberInstance.DBInstances[0].DBInstanceClass),
+			Engine:        aws.ToString(dbCluster.Engine),
+			MultiAZ:       false,
+		})
+		if err != nil {
+			return 0, err
+		}
+		total += hourly * hoursPerMonth
+	}
+
+	storagePerGB, err := conn.Pricer.RDSStoragePrice(ctx, pricing.RDSStoragePriceInput{
+		Region:      conn.Region,
+		StorageType: "aurora",
+		Engine:      aws.ToString(dbCluster.Engine),
+	})
+	if err != nil {
+		return 0, err
+	}
+	total += storagePerGB * storageGB
+
+	return int(total), nil
+}
@@ -0,0 +1,135 @@
+package cost
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func TestIsServerless(t *testing.T) {
+	tests := []struct {
+		name      string
+		dbCluster rdsTypes.DBCluster
+		want      bool
+	}{
+		{
+			name:      "provisioned cluster with no serverless config is not serverless",
+			dbCluster: rdsTypes.DBCluster{EngineMode: aws.String("provisioned")},
+			want:      false,
+		},
+		{
+			name:      "engine mode serverless is Aurora Serverless v1",
+			dbCluster: rdsTypes.DBCluster{EngineMode: aws.String("serverless")},
+			want:      true,
+		},
+		{
+			name: "a serverless v2 scaling configuration is Aurora Serverless v2",
+			dbCluster: rdsTypes.DBCluster{
+				EngineMode:                       aws.String("provisioned"),
+				ServerlessV2ScalingConfiguration: &rdsTypes.ServerlessV2ScalingConfigurationInfo{MinCapacity: aws.Float64(0.5)},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isServerless(tt.dbCluster); got != tt.want {
+				t.Errorf("isServerless() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcuFloorIdle(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		dataPoints []types.Datapoint
+		minACU     float64
+		want       bool
+	}{
+		{
+			name:       "no datapoints stays at the floor",
+			dataPoints: nil,
+			minACU:     0.5,
+			want:       true,
+		},
+		{
+			name: "every datapoint at or below the floor is idle",
+			dataPoints: []types.Datapoint{
+				{Average: aws.Float64(0.5), Timestamp: aws.Time(now)},
+				{Average: aws.Float64(0.25), Timestamp: aws.Time(now)},
+			},
+			minACU: 0.5,
+			want:   true,
+		},
+		{
+			name: "a single datapoint above the floor is not idle",
+			dataPoints: []types.Datapoint{
+				{Average: aws.Float64(0.5), Timestamp: aws.Time(now)},
+				{Average: aws.Float64(2), Timestamp: aws.Time(now)},
+			},
+			minACU: 0.5,
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acuFloorIdle(tt.dataPoints, tt.minACU); got != tt.want {
+				t.Errorf("acuFloorIdle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountClusterMembers(t *testing.T) {
+	tests := []struct {
+		name            string
+		members         []rdsTypes.DBClusterMember
+		wantWriterCount int
+		wantReaderCount int
+	}{
+		{
+			name:            "no members",
+			members:         nil,
+			wantWriterCount: 0,
+			wantReaderCount: 0,
+		},
+		{
+			name: "one writer and two readers",
+			members: []rdsTypes.DBClusterMember{
+				{IsClusterWriter: aws.Bool(true)},
+				{IsClusterWriter: aws.Bool(false)},
+				{IsClusterWriter: aws.Bool(false)},
+			},
+			wantWriterCount: 1,
+			wantReaderCount: 2,
+		},
+		{
+			name: "writer only",
+			members: []rdsTypes.DBClusterMember{
+				{IsClusterWriter: aws.Bool(true)},
+			},
+			wantWriterCount: 1,
+			wantReaderCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotWriterCount, gotReaderCount := countClusterMembers(tt.members)
+			if gotWriterCount != tt.wantWriterCount {
+				t.Errorf("countClusterMembers() writerCount = %v, want %v", gotWriterCount, tt.wantWriterCount)
+			}
+			if gotReaderCount != tt.wantReaderCount {
+				t.Errorf("countClusterMembers() readerCount = %v, want %v", gotReaderCount, tt.wantReaderCount)
+			}
+		})
+	}
+}
@@ -9,10 +9,33 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/brittandeyoung/ckia/internal/aws/cost/pricing"
 	"github.com/brittandeyoung/ckia/internal/client"
 	"github.com/brittandeyoung/ckia/internal/common"
 )
 
+// RunMultiRegion fans IdleDBInstancesCheck.Run out across regions using
+// common.MultiRegionRunner, merging every region's idle instances into a
+// single check result.
+func (v IdleDBInstancesCheck) RunMultiRegion(ctx context.Context, cfg common.MultiRegionRunnerConfig, newClient common.ClientFactory, config IdleDBInstancesConfig) (*IdleDBInstancesCheck, error) {
+	check := new(IdleDBInstancesCheck).List(config)
+
+	rows, err := common.MultiRegionRunner(ctx, cfg, newClient, func(ctx context.Context, conn client.AWSClient) ([]IdleDBInstance, error) {
+		result, err := new(IdleDBInstancesCheck).Run(ctx, conn, config)
+		if err != nil || result == nil {
+			return nil, err
+		}
+		return result.IdleDBInstances, nil
+	})
+
+	check.IdleDBInstances = rows
+	return check, err
+}
+
+// hoursPerMonth is the constant used to project an hourly on-demand rate to
+// a monthly cost, matching how AWS Trusted Advisor reports RDS savings.
+const hoursPerMonth = 730
+
 const (
 	IdleDBInstancesCheckId                  = "ckia:aws:cost:IdleDBInstances"
 	IdleDBInstancesCheckName                = "RDS Idle DB Instances"
@@ -37,7 +60,49 @@ type IdleDBInstancesCheck struct {
 	IdleDBInstances []IdleDBInstance `json:"idleDBInstances"`
 }
 
-func (v IdleDBInstancesCheck) List() *IdleDBInstancesCheck {
+// IdleDBInstancesConfig controls how aggressively IdleDBInstancesCheck flags
+// a DB instance as idle. Zero-valued fields fall back to DefaultIdleDBInstancesConfig,
+// so existing callers passing IdleDBInstancesConfig{} keep today's behavior.
+type IdleDBInstancesConfig struct {
+	// IdleThresholdDays is how long a DB instance can go without a
+	// connection before it is reported as idle.
+	IdleThresholdDays int32 `json:"idleThresholdDays,omitempty"`
+	// LookbackDays is how far back CloudWatch connection metrics are queried.
+	LookbackDays int32 `json:"lookbackDays,omitempty"`
+	// MinAverageConnections lets an instance with a trickle of connections
+	// (e.g. monitoring probes) still be treated as idle.
+	MinAverageConnections float64 `json:"minAverageConnections,omitempty"`
+	// MetricPeriodSeconds is the CloudWatch statistic period used when
+	// querying DatabaseConnections.
+	MetricPeriodSeconds int32 `json:"metricPeriodSeconds,omitempty"`
+}
+
+// DefaultIdleDBInstancesConfig returns the idle-detection window and
+// threshold IdleDBInstancesCheck used before it became configurable.
+func DefaultIdleDBInstancesConfig() IdleDBInstancesConfig {
+	return IdleDBInstancesConfig{
+		IdleThresholdDays:     7,
+		LookbackDays:          14,
+		MinAverageConnections: 0,
+		MetricPeriodSeconds:   3600,
+	}
+}
+
+func (c IdleDBInstancesConfig) withDefaults() IdleDBInstancesConfig {
+	defaults := DefaultIdleDBInstancesConfig()
+	if c.IdleThresholdDays == 0 {
+		c.IdleThresholdDays = defaults.IdleThresholdDays
+	}
+	if c.LookbackDays == 0 {
+		c.LookbackDays = defaults.LookbackDays
+	}
+	if c.MetricPeriodSeconds == 0 {
+		c.MetricPeriodSeconds = defaults.MetricPeriodSeconds
+	}
+	return c
+}
+
+func (v IdleDBInstancesCheck) List(config IdleDBInstancesConfig) *IdleDBInstancesCheck {
 	check := &IdleDBInstancesCheck{
 		Check: common.Check{
 			Id:                  IdleDBInstancesCheckId,
@@ -46,13 +111,15 @@ func (v IdleDBInstancesCheck) List() *IdleDBInstancesCheck {
 			Criteria:            IdleDBInstancesCheckCriteria,
 			RecommendedAction:   IdleDBInstancesCheckRecommendedAction,
 			AdditionalResources: IdleDBInstancesCheckAdditionalResources,
+			Config:              config.withDefaults(),
 		},
 	}
 	return check
 }
 
-func (v IdleDBInstancesCheck) Run(ctx context.Context, conn client.AWSClient) (*IdleDBInstancesCheck, error) {
-	check := new(IdleDBInstancesCheck).List()
+func (v IdleDBInstancesCheck) Run(ctx context.Context, conn client.AWSClient, config IdleDBInstancesConfig) (*IdleDBInstancesCheck, error) {
+	config = config.withDefaults()
+	check := new(IdleDBInstancesCheck).List(config)
 
 	currentTime := time.Now()
 
@@ -80,7 +147,7 @@ func (v IdleDBInstancesCheck) Run(ctx context.Context, conn client.AWSClient) (*
 
 		metrics, err := conn.Cloudwatch.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
 			MetricName: aws.String("DatabaseConnections"),
-			Period:     aws.Int32(3600),
+			Period:     aws.Int32(config.MetricPeriodSeconds),
 			Namespace:  aws.String("AWS/RDS"),
 			Statistics: []types.Statistic{types.StatisticAverage},
 			Dimensions: []types.Dimension{
@@ -89,7 +156,7 @@ func (v IdleDBInstancesCheck) Run(ctx context.Context, conn client.AWSClient) (*
 					Value: dbInstance.DBInstanceIdentifier,
 				},
 			},
-			StartTime: aws.Time(currentTime.AddDate(0, 0, -14)),
+			StartTime: aws.Time(currentTime.AddDate(0, 0, -int(config.LookbackDays))),
 			EndTime:   aws.Time(currentTime),
 		})
 
@@ -98,67 +165,22 @@ func (v IdleDBInstancesCheck) Run(ctx context.Context, conn client.AWSClient) (*
 		}
 
 		var idleDBInstance IdleDBInstance
-		daysSinceConnection, connectionFound := expandConnections(metrics.Datapoints)
+		daysSinceConnection, connectionFound := expandConnections(metrics.Datapoints, config)
 
 		if !connectionFound {
-			// pricingSvc := pricing.NewFromConfig(cfg)
-			// filters := []pricingtypes.Filter{
-			// 	{
-			// 		Field: aws.String("InstanceType"),
-			// 		Type:  "TERM_MATCH",
-			// 		Value: dbInstance.DBInstanceClass,
-			// 	},
-			// 	// These two seam to not match what the pricing API is expecting
-			// 	{
-			// 		Field: aws.String("storage"),
-			// 		Type:  "TERM_MATCH",
-			// 		Value: dbInstance.StorageType,
-			// 	},
-			// 	{
-			// 		Field: aws.String("databaseEngine"),
-			// 		Type:  "TERM_MATCH",
-			// 		Value: dbInstance.Engine,
-			// 	},
-			// 	{
-			// 		Field: aws.String("deploymentOption"),
-			// 		Type:  "TERM_MATCH",
-			// 		Value: aws.String("Single-AZ"),
-			// 	},
-			// 	{
-			// 		Field: aws.String("termType"),
-			// 		Type:  "TERM_MATCH",
-			// 		Value: aws.String("OnDemand"),
-			// 	},
-			// 	{
-			// 		Field: aws.String("regionCode"),
-			// 		Type:  "TERM_MATCH",
-			// 		Value: &cfg.Region,
-			// 	},
-			// 	{
-			// 		Field: aws.String("purchaseOption"),
-			// 		Type:  "TERM_MATCH",
-			// 		Value: aws.String("No Upfront"),
-			// 	},
-			// }
-
-			// pricingIn := &pricing.GetProductsInput{
-			// 	ServiceCode: aws.String("AmazonRDS"),
-			// 	Filters:     filters,
-			// }
-			// pricingData, err := pricingSvc.GetProducts(ctx, pricingIn)
-
-			// if err != nil {
-			// 	return IdleDBInstancesCheck{}
-			// }
-
 			idleDBInstance.DBInstanceName = aws.ToString(dbInstance.DBInstanceIdentifier)
 			idleDBInstance.Region = conn.Region
 			idleDBInstance.DaysSinceLastConnection = daysSinceConnection
 			idleDBInstance.InstanceType = aws.ToString(dbInstance.DBInstanceClass)
-			idleDBInstance.MultiAZ = dbInstance.MultiAZ
-			idleDBInstance.StorageProvisionedInGB = int(dbInstance.AllocatedStorage)
-			// Still trying to figure out how to get the proper on demand pricing via the API
-			// idleDBInstance.EstimatedMonthlySavings = 0
+			idleDBInstance.MultiAZ = aws.ToBool(dbInstance.MultiAZ)
+			idleDBInstance.StorageProvisionedInGB = int(aws.ToInt32(dbInstance.AllocatedStorage))
+
+			savings, err := estimateMonthlySavings(ctx, conn.Pricer, conn.Region, dbInstance)
+			if err != nil {
+				return nil, err
+			}
+			idleDBInstance.EstimatedMonthlySavings = savings
+
 			idleDBInstances = append(idleDBInstances, idleDBInstance)
 		}
 
@@ -168,21 +190,60 @@ func (v IdleDBInstancesCheck) Run(ctx context.Context, conn client.AWSClient) (*
 	return check, nil
 }
 
-func expandConnections(dataPoints []types.Datapoint) (int, bool) {
+// expandConnections returns the number of days since the most recent
+// connection above config.MinAverageConnections, and whether that most
+// recent connection falls within config.IdleThresholdDays (i.e. the
+// instance is NOT idle).
+func expandConnections(dataPoints []types.Datapoint, config IdleDBInstancesConfig) (int, bool) {
 	connectionFound := false
-	var daysSinceConnection float64
-	daysSinceConnection = 14
+	daysSinceConnection := float64(config.LookbackDays)
 	for _, dataPoint := range dataPoints {
-		if aws.ToFloat64(dataPoint.Average) != 0 {
+		if aws.ToFloat64(dataPoint.Average) > config.MinAverageConnections {
 			duration := time.Now().Sub(aws.ToTime(dataPoint.Timestamp))
 			if duration.Hours()/24 < daysSinceConnection {
 				daysSinceConnection = duration.Hours() / 24
 			}
 
-			if duration.Hours()/24 <= 7 {
+			if duration.Hours()/24 <= float64(config.IdleThresholdDays) {
 				connectionFound = true
 			}
 		}
 	}
 	return int(daysSinceConnection), connectionFound
 }
+
+// estimateMonthlySavings sums the on-demand compute, storage, and (for io1)
+// provisioned IOPS cost of running dbInstance for a full month. Any price
+// the pricer cannot find is treated as zero rather than failing the check.
+func estimateMonthlySavings(ctx context.Context, pricer pricing.Pricer, region string, dbInstance rdsTypes.DBInstance) (int, error) {
+	hourly, err := pricer.RDSInstancePrice(ctx, pricing.RDSInstancePriceInput{
+		Region:        region,
+		InstanceClass: aws.ToString(dbInstance.DBInstanceClass),
+		Engine:        aws.ToString(dbInstance.Engine),
+		MultiAZ:       aws.ToBool(dbInstance.MultiAZ),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	storagePerGB, err := pricer.RDSStoragePrice(ctx, pricing.RDSStoragePriceInput{
+		Region:      region,
+		StorageType: aws.ToString(dbInstance.StorageType),
+		Engine:      aws.ToString(dbInstance.Engine),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	total := hourly*hoursPerMonth + storagePerGB*float64(aws.ToInt32(dbInstance.AllocatedStorage))
+
+	if aws.ToString(dbInstance.StorageType) == "io1" {
+		iopsPrice, err := pricer.RDSIOPSPrice(ctx, pricing.RDSIOPSPriceInput{Region: region})
+		if err != nil {
+			return 0, err
+		}
+		total += iopsPrice * float64(aws.ToInt32(dbInstance.Iops))
+	}
+
+	return int(total), nil
+}
@@ -0,0 +1,107 @@
+package cost
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+func TestExpandConnections(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name                string
+		dataPoints          []types.Datapoint
+		config              IdleDBInstancesConfig
+		wantDaysSince       int
+		wantConnectionFound bool
+	}{
+		{
+			name:       "no datapoints is idle for the full lookback window",
+			dataPoints: nil,
+			config: IdleDBInstancesConfig{
+				IdleThresholdDays: 7,
+				LookbackDays:      14,
+			},
+			wantDaysSince:       14,
+			wantConnectionFound: false,
+		},
+		{
+			name: "connection within the idle threshold is not idle",
+			dataPoints: []types.Datapoint{
+				{Average: aws.Float64(2), Timestamp: aws.Time(now.AddDate(0, 0, -3))},
+			},
+			config: IdleDBInstancesConfig{
+				IdleThresholdDays: 7,
+				LookbackDays:      14,
+			},
+			wantDaysSince:       3,
+			wantConnectionFound: true,
+		},
+		{
+			name: "connection outside the idle threshold is idle",
+			dataPoints: []types.Datapoint{
+				{Average: aws.Float64(2), Timestamp: aws.Time(now.AddDate(0, 0, -10))},
+			},
+			config: IdleDBInstancesConfig{
+				IdleThresholdDays: 7,
+				LookbackDays:      14,
+			},
+			wantDaysSince:       10,
+			wantConnectionFound: false,
+		},
+		{
+			name: "average at or below MinAverageConnections is treated as no connection",
+			dataPoints: []types.Datapoint{
+				{Average: aws.Float64(1), Timestamp: aws.Time(now.AddDate(0, 0, -1))},
+			},
+			config: IdleDBInstancesConfig{
+				IdleThresholdDays:     7,
+				LookbackDays:          14,
+				MinAverageConnections: 1,
+			},
+			wantDaysSince:       14,
+			wantConnectionFound: false,
+		},
+		{
+			name: "average above MinAverageConnections counts as a connection",
+			dataPoints: []types.Datapoint{
+				{Average: aws.Float64(1.5), Timestamp: aws.Time(now.AddDate(0, 0, -1))},
+			},
+			config: IdleDBInstancesConfig{
+				IdleThresholdDays:     7,
+				LookbackDays:          14,
+				MinAverageConnections: 1,
+			},
+			wantDaysSince:       1,
+			wantConnectionFound: true,
+		},
+		{
+			name: "the most recent qualifying datapoint wins",
+			dataPoints: []types.Datapoint{
+				{Average: aws.Float64(2), Timestamp: aws.Time(now.AddDate(0, 0, -10))},
+				{Average: aws.Float64(2), Timestamp: aws.Time(now.AddDate(0, 0, -2))},
+			},
+			config: IdleDBInstancesConfig{
+				IdleThresholdDays: 7,
+				LookbackDays:      14,
+			},
+			wantDaysSince:       2,
+			wantConnectionFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDaysSince, gotConnectionFound := expandConnections(tt.dataPoints, tt.config)
+			if gotConnectionFound != tt.wantConnectionFound {
+				t.Errorf("expandConnections() connectionFound = %v, want %v", gotConnectionFound, tt.wantConnectionFound)
+			}
+			if gotDaysSince != tt.wantDaysSince {
+				t.Errorf("expandConnections() daysSinceConnection = %v, want %v", gotDaysSince, tt.wantDaysSince)
+			}
+		})
+	}
+}
@@ -0,0 +1,502 @@
+// RDS has no spot market, so this check does not call
+// DescribeSpotPriceHistoryPages the way the EC2-oriented prior art it was
+// modeled on does; on-demand vs. on-demand (current class vs. recommended
+// class) is the only comparison that applies here. The idle/underutilized
+// split below is what stands in for that comparison: idle instances get a
+// delete-sized estimate (the full instance cost, from estimateMonthlySavings),
+// underutilized instances get a downsize-sized estimate (the delta between
+// the current and recommended class, from estimateDownsizeSavings).
+package cost
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/brittandeyoung/ckia/internal/aws/cost/pricing"
+	"github.com/brittandeyoung/ckia/internal/client"
+	"github.com/brittandeyoung/ckia/internal/common"
+)
+
+const (
+	RightsizeDBInstancesCheckId                  = "ckia:aws:cost:RightsizeDBInstances"
+	RightsizeDBInstancesCheckName                = "RDS Rightsize DB Instances"
+	RightsizeDBInstancesCheckDescription         = "Checks your Amazon RDS DB instances' historical CPU, memory, connection, and IOPS utilization to find instances that are bigger than they need to be. Unlike the idle DB instances check, this looks at instances that ARE being used, but could run on a smaller, cheaper instance class without a meaningful risk of running out of headroom."
+	RightsizeDBInstancesCheckCriteria            = "Any RDS DB instance whose p95 CPU utilization and freeable memory over the last 30 days leave significant headroom is considered underutilized and a smaller instance class is recommended."
+	RightsizeDBInstancesCheckRecommendedAction   = "Consider downsizing underutilized DB instances to the recommended instance class during a maintenance window. Validate the recommendation against your own peak traffic expectations before changing production instances, since CloudWatch metrics only reflect the lookback window evaluated."
+	RightsizeDBInstancesCheckAdditionalResources = "See comparable AWS Trusted advisor check: https://docs.aws.amazon.com/awssupport/latest/user/cost-optimization-checks.html#amazon-rds-idle-dbs-instances"
+)
+
+// UtilizationClass buckets a DB instance by how much of its current
+// capacity it is actually using.
+type UtilizationClass string
+
+const (
+	UtilizationIdle          UtilizationClass = "idle"
+	UtilizationUnderutilized UtilizationClass = "underutilized"
+	UtilizationRightSized    UtilizationClass = "right-sized"
+)
+
+// UtilizationProfile summarizes a DB instance's CloudWatch metrics over the
+// check's lookback window.
+type UtilizationProfile struct {
+	AverageConnections     float64          `json:"averageConnections"`
+	P95CPUUtilization      float64          `json:"p95CPUUtilization"`
+	P95FreeableMemoryBytes float64          `json:"p95FreeableMemoryBytes"`
+	P95ReadIOPS            float64          `json:"p95ReadIOPS"`
+	P95WriteIOPS           float64          `json:"p95WriteIOPS"`
+	Class                  UtilizationClass `json:"class"`
+}
+
+type RightsizeDBInstance struct {
+	Region                  string             `json:"region"`
+	DBInstanceName          string             `json:"dbInstanceName"`
+	InstanceType            string             `json:"instanceType"`
+	RecommendedInstanceType string             `json:"recommendedInstanceType,omitempty"`
+	Utilization             UtilizationProfile `json:"utilization"`
+	EstimatedMonthlySavings int                `json:"estimatedMonthlySavings"`
+}
+
+type RightsizeDBInstancesCheck struct {
+	common.Check
+	RightsizeDBInstances []RightsizeDBInstance `json:"rightsizeDBInstances"`
+}
+
+// RightsizeDBInstancesConfig controls the lookback window and the
+// thresholds used to classify a DB instance's utilization.
+type RightsizeDBInstancesConfig struct {
+	// LookbackDays is how far back CloudWatch utilization metrics are queried.
+	LookbackDays int32 `json:"lookbackDays,omitempty"`
+	// MetricPeriodSeconds is the CloudWatch statistic period used for every
+	// utilization metric.
+	MetricPeriodSeconds int32 `json:"metricPeriodSeconds,omitempty"`
+	// IdleThresholdDays and MinAverageConnections classify an instance as
+	// idle, matching IdleDBInstancesConfig's semantics.
+	IdleThresholdDays     int32   `json:"idleThresholdDays,omitempty"`
+	MinAverageConnections float64 `json:"minAverageConnections,omitempty"`
+	// UnderutilizedCPUPercent is the p95 CPU utilization below which an
+	// instance is a downsize candidate.
+	UnderutilizedCPUPercent float64 `json:"underutilizedCPUPercent,omitempty"`
+	// UnderutilizedMemoryHeadroomPercent is the minimum fraction of an
+	// instance's memory that must stay free for it to be a downsize candidate.
+	UnderutilizedMemoryHeadroomPercent float64 `json:"underutilizedMemoryHeadroomPercent,omitempty"`
+}
+
+// DefaultRightsizeDBInstancesConfig returns a 30 day lookback with
+// thresholds loose enough to avoid recommending a downsize on a spiky but
+// otherwise healthy instance.
+func DefaultRightsizeDBInstancesConfig() RightsizeDBInstancesConfig {
+	return RightsizeDBInstancesConfig{
+		LookbackDays:                       30,
+		MetricPeriodSeconds:                3600,
+		IdleThresholdDays:                  7,
+		MinAverageConnections:              0,
+		UnderutilizedCPUPercent:            40,
+		UnderutilizedMemoryHeadroomPercent: 50,
+	}
+}
+
+func (c RightsizeDBInstancesConfig) withDefaults() RightsizeDBInstancesConfig {
+	defaults := DefaultRightsizeDBInstancesConfig()
+	if c.LookbackDays == 0 {
+		c.LookbackDays = defaults.LookbackDays
+	}
+	if c.MetricPeriodSeconds == 0 {
+		c.MetricPeriodSeconds = defaults.MetricPeriodSeconds
+	}
+	if c.IdleThresholdDays == 0 {
+		c.IdleThresholdDays = defaults.IdleThresholdDays
+	}
+	if c.UnderutilizedCPUPercent == 0 {
+		c.UnderutilizedCPUPercent = defaults.UnderutilizedCPUPercent
+	}
+	if c.UnderutilizedMemoryHeadroomPercent == 0 {
+		c.UnderutilizedMemoryHeadroomPercent = defaults.UnderutilizedMemoryHeadroomPercent
+	}
+	return c
+}
+
+func (v RightsizeDBInstancesCheck) List(config RightsizeDBInstancesConfig) *RightsizeDBInstancesCheck {
+	check := &RightsizeDBInstancesCheck{
+		Check: common.Check{
+			Id:                  RightsizeDBInstancesCheckId,
+			Name:                RightsizeDBInstancesCheckName,
+			Description:         RightsizeDBInstancesCheckDescription,
+			Criteria:            RightsizeDBInstancesCheckCriteria,
+			RecommendedAction:   RightsizeDBInstancesCheckRecommendedAction,
+			AdditionalResources: RightsizeDBInstancesCheckAdditionalResources,
+			Config:              config.withDefaults(),
+		},
+	}
+	return check
+}
+
+// RunMultiRegion fans RightsizeDBInstancesCheck.Run out across regions using
+// common.MultiRegionRunner, merging every region's rightsizing candidates
+// into a single check result.
+func (v RightsizeDBInstancesCheck) RunMultiRegion(ctx context.Context, cfg common.MultiRegionRunnerConfig, newClient common.ClientFactory, config RightsizeDBInstancesConfig) (*RightsizeDBInstancesCheck, error) {
+	check := new(RightsizeDBInstancesCheck).List(config)
+
+	rows, err := common.MultiRegionRunner(ctx, cfg, newClient, func(ctx context.Context, conn client.AWSClient) ([]RightsizeDBInstance, error) {
+		result, err := new(RightsizeDBInstancesCheck).Run(ctx, conn, config)
+		if err != nil || result == nil {
+			return nil, err
+		}
+		return result.RightsizeDBInstances, nil
+	})
+
+	check.RightsizeDBInstances = rows
+	return check, err
+}
+
+func (v RightsizeDBInstancesCheck) Run(ctx context.Context, conn client.AWSClient, config RightsizeDBInstancesConfig) (*RightsizeDBInstancesCheck, error) {
+	config = config.withDefaults()
+	check := new(RightsizeDBInstancesCheck).List(config)
+
+	currentTime := time.Now()
+
+	in := &rds.DescribeDBInstancesInput{}
+	var dbInstances []rdsTypes.DBInstance
+
+	paginator := rds.NewDescribeDBInstancesPaginator(conn.RDS, in, func(o *rds.DescribeDBInstancesPaginatorOptions) {})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		dbInstances = append(dbInstances, output.DBInstances...)
+	}
+
+	if len(dbInstances) == 0 {
+		return nil, nil
+	}
+
+	var rightsizeDBInstances []RightsizeDBInstance
+	for _, dbInstance := range dbInstances {
+		profile, err := buildUtilizationProfile(ctx, conn, dbInstance, config, currentTime)
+		if err != nil {
+			return nil, err
+		}
+
+		rightsizeDBInstance := RightsizeDBInstance{
+			Region:         conn.Region,
+			DBInstanceName: aws.ToString(dbInstance.DBInstanceIdentifier),
+			InstanceType:   aws.ToString(dbInstance.DBInstanceClass),
+			Utilization:    profile,
+		}
+
+		switch profile.Class {
+		case UtilizationIdle:
+			savings, err := estimateMonthlySavings(ctx, conn.Pricer, conn.Region, dbInstance)
+			if err != nil {
+				return nil, err
+			}
+			rightsizeDBInstance.EstimatedMonthlySavings = savings
+
+		case UtilizationUnderutilized:
+			recommended, err := recommendInstanceClass(ctx, conn, dbInstance, profile, config)
+			if err != nil {
+				return nil, err
+			}
+			if recommended != "" {
+				rightsizeDBInstance.RecommendedInstanceType = recommended
+
+				savings, err := estimateDownsizeSavings(ctx, conn.Pricer, conn.Region, dbInstance, recommended)
+				if err != nil {
+					return nil, err
+				}
+				rightsizeDBInstance.EstimatedMonthlySavings = savings
+			}
+		}
+
+		rightsizeDBInstances = append(rightsizeDBInstances, rightsizeDBInstance)
+	}
+
+	check.RightsizeDBInstances = rightsizeDBInstances
+	return check, nil
+}
+
+// buildUtilizationProfile pulls 30 days (by default) of CloudWatch metrics
+// for dbInstance and classifies it as idle, underutilized, or right-sized.
+func buildUtilizationProfile(ctx context.Context, conn client.AWSClient, dbInstance rdsTypes.DBInstance, config RightsizeDBInstancesConfig, currentTime time.Time) (UtilizationProfile, error) {
+	dimensions := []types.Dimension{
+		{
+			Name:  aws.String("DBInstanceIdentifier"),
+			Value: dbInstance.DBInstanceIdentifier,
+		},
+	}
+	startTime := aws.Time(currentTime.AddDate(0, 0, -int(config.LookbackDays)))
+	endTime := aws.Time(currentTime)
+
+	connections, err := conn.Cloudwatch.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		MetricName: aws.String("DatabaseConnections"),
+		Namespace:  aws.String("AWS/RDS"),
+		Period:     aws.Int32(config.MetricPeriodSeconds),
+		Statistics: []types.Statistic{types.StatisticAverage},
+		Dimensions: dimensions,
+		StartTime:  startTime,
+		EndTime:    endTime,
+	})
+	if err != nil {
+		return UtilizationProfile{}, err
+	}
+
+	cpu, err := p95MetricStatistic(ctx, conn, "CPUUtilization", dimensions, config, startTime, endTime, maxOfValues)
+	if err != nil {
+		return UtilizationProfile{}, err
+	}
+
+	// FreeableMemory is how much RAM is NOT in use, so the worst-case (most
+	// memory pressure) point over the window is the minimum, not the maximum.
+	minFreeableMemory, err := p95MetricStatistic(ctx, conn, "FreeableMemory", dimensions, config, startTime, endTime, minOfValues)
+	if err != nil {
+		return UtilizationProfile{}, err
+	}
+
+	readIOPS, err := p95MetricStatistic(ctx, conn, "ReadIOPS", dimensions, config, startTime, endTime, maxOfValues)
+	if err != nil {
+		return UtilizationProfile{}, err
+	}
+
+	writeIOPS, err := p95MetricStatistic(ctx, conn, "WriteIOPS", dimensions, config, startTime, endTime, maxOfValues)
+	if err != nil {
+		return UtilizationProfile{}, err
+	}
+
+	profile := UtilizationProfile{
+		AverageConnections:     averageDatapoint(connections.Datapoints),
+		P95CPUUtilization:      cpu,
+		P95FreeableMemoryBytes: minFreeableMemory,
+		P95ReadIOPS:            readIOPS,
+		P95WriteIOPS:           writeIOPS,
+	}
+
+	_, connectionFound := expandConnections(connections.Datapoints, IdleDBInstancesConfig{
+		IdleThresholdDays:     config.IdleThresholdDays,
+		LookbackDays:          config.LookbackDays,
+		MinAverageConnections: config.MinAverageConnections,
+	})
+
+	memorySpec, knownSpec := instanceSpecs[aws.ToString(dbInstance.DBInstanceClass)]
+
+	profile.Class = classifyUtilization(connectionFound, knownSpec, profile.P95CPUUtilization, profile.P95FreeableMemoryBytes, memorySpec.memoryGiB, config)
+
+	return profile, nil
+}
+
+// classifyUtilization buckets a DB instance given its worst-case CPU and
+// freeable-memory readings. It is a pure function of its inputs so the
+// thresholds can be unit tested without CloudWatch.
+func classifyUtilization(connectionFound, knownSpec bool, p95CPUUtilization, minFreeableMemoryBytes, totalMemoryGiB float64, config RightsizeDBInstancesConfig) UtilizationClass {
+	switch {
+	case !connectionFound:
+		return UtilizationIdle
+	case knownSpec && p95CPUUtilization < config.UnderutilizedCPUPercent &&
+		minFreeableMemoryBytes > totalMemoryGiB*bytesPerGiB*(config.UnderutilizedMemoryHeadroomPercent/100):
+		return UtilizationUnderutilized
+	default:
+		return UtilizationRightSized
+	}
+}
+
+// p95MetricStatistic queries a single CloudWatch metric with the p95
+// extended statistic and reduces its datapoints with aggregate. Use
+// maxOfValues for metrics where the worst case is the highest reading (CPU,
+// IOPS) and minOfValues for metrics where the worst case is the lowest
+// reading (FreeableMemory).
+func p95MetricStatistic(ctx context.Context, conn client.AWSClient, metricName string, dimensions []types.Dimension, config RightsizeDBInstancesConfig, startTime, endTime *time.Time, aggregate func([]float64) float64) (float64, error) {
+	output, err := conn.Cloudwatch.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		MetricName:         aws.String(metricName),
+		Namespace:          aws.String("AWS/RDS"),
+		Period:             aws.Int32(config.MetricPeriodSeconds),
+		ExtendedStatistics: []string{"p95"},
+		Dimensions:         dimensions,
+		StartTime:          startTime,
+		EndTime:            endTime,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	values := make([]float64, 0, len(output.Datapoints))
+	for _, dataPoint := range output.Datapoints {
+		if value, ok := dataPoint.ExtendedStatistics["p95"]; ok {
+			values = append(values, value)
+		}
+	}
+	return aggregate(values), nil
+}
+
+func maxOfValues(values []float64) float64 {
+	var max float64
+	for _, value := range values {
+		if value > max {
+			max = value
+		}
+	}
+	return max
+}
+
+func minOfValues(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+	return min
+}
+
+func averageDatapoint(dataPoints []types.Datapoint) float64 {
+	if len(dataPoints) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, dataPoint := range dataPoints {
+		sum += aws.ToFloat64(dataPoint.Average)
+	}
+	return sum / float64(len(dataPoints))
+}
+
+const bytesPerGiB = 1024 * 1024 * 1024
+
+// instanceSpec is the best-effort vCPU/memory shape of a DB instance class,
+// used to pick a smaller class that still fits observed utilization.
+// AWS does not return these via DescribeOrderableDBInstanceOptions, so this
+// table only needs to cover the classes rightsizing decisions are made
+// between; it can be extended as new families come up in practice.
+type instanceSpec struct {
+	vCPU      int
+	memoryGiB float64
+}
+
+var instanceSpecs = map[string]instanceSpec{
+	"db.t3.micro":   {vCPU: 2, memoryGiB: 1},
+	"db.t3.small":   {vCPU: 2, memoryGiB: 2},
+	"db.t3.medium":  {vCPU: 2, memoryGiB: 4},
+	"db.t3.large":   {vCPU: 2, memoryGiB: 8},
+	"db.m5.large":   {vCPU: 2, memoryGiB: 8},
+	"db.m5.xlarge":  {vCPU: 4, memoryGiB: 16},
+	"db.m5.2xlarge": {vCPU: 8, memoryGiB: 32},
+	"db.m5.4xlarge": {vCPU: 16, memoryGiB: 64},
+	"db.r5.large":   {vCPU: 2, memoryGiB: 16},
+	"db.r5.xlarge":  {vCPU: 4, memoryGiB: 32},
+	"db.r5.2xlarge": {vCPU: 8, memoryGiB: 64},
+	"db.r5.4xlarge": {vCPU: 16, memoryGiB: 128},
+}
+
+// candidateFits reports whether candidate is a smaller class than current
+// that still fits the instance's p95 CPU and memory usage with headroom. It
+// is a pure function of its inputs so the fit rules can be unit tested
+// without DescribeOrderableDBInstanceOptions.
+func candidateFits(current, candidate instanceSpec, p95CPUUtilization, requiredMemoryGiB float64, config RightsizeDBInstancesConfig) bool {
+	if candidate.vCPU >= current.vCPU || candidate.memoryGiB >= current.memoryGiB {
+		return false
+	}
+	if candidate.memoryGiB < requiredMemoryGiB {
+		return false
+	}
+	// p95 CPU utilization is relative to the current class's vCPU count, so
+	// it has to be rescaled against the candidate's vCPU count before
+	// comparing to the threshold - fewer vCPUs at the same absolute load
+	// means higher utilization.
+	scaledCPUUtilization := p95CPUUtilization * float64(current.vCPU) / float64(candidate.vCPU)
+	return scaledCPUUtilization < config.UnderutilizedCPUPercent
+}
+
+// recommendInstanceClass enumerates the valid target classes for
+// dbInstance's engine/version/storage via DescribeOrderableDBInstanceOptions
+// and returns the smallest one (by the instanceSpecs table) that still fits
+// p95 CPU and memory usage with headroom. It returns "" when no valid,
+// smaller, known class is found.
+func recommendInstanceClass(ctx context.Context, conn client.AWSClient, dbInstance rdsTypes.DBInstance, profile UtilizationProfile, config RightsizeDBInstancesConfig) (string, error) {
+	currentSpec, ok := instanceSpecs[aws.ToString(dbInstance.DBInstanceClass)]
+	if !ok {
+		return "", nil
+	}
+
+	// profile.P95FreeableMemoryBytes is the minimum freeable memory observed,
+	// i.e. memory that stayed free even at the instance's peak usage. The
+	// target class must fit the memory actually USED at that peak, plus
+	// headroom - not the memory that was free, which is the inverse figure.
+	usedMemoryGiB := currentSpec.memoryGiB - profile.P95FreeableMemoryBytes/bytesPerGiB
+	if usedMemoryGiB < 0 {
+		usedMemoryGiB = 0
+	}
+	requiredMemoryGiB := usedMemoryGiB * (1 + config.UnderutilizedMemoryHeadroomPercent/100)
+
+	in := &rds.DescribeOrderableDBInstanceOptionsInput{
+		Engine:        dbInstance.Engine,
+		EngineVersion: dbInstance.EngineVersion,
+	}
+
+	var best string
+	var bestSpec instanceSpec
+
+	paginator := rds.NewDescribeOrderableDBInstanceOptionsPaginator(conn.RDS, in, func(o *rds.DescribeOrderableDBInstanceOptionsPaginatorOptions) {})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		for _, option := range output.OrderableDBInstanceOptions {
+			class := aws.ToString(option.DBInstanceClass)
+			spec, known := instanceSpecs[class]
+			if !known || class == aws.ToString(dbInstance.DBInstanceClass) {
+				continue
+			}
+			if aws.ToString(option.StorageType) != aws.ToString(dbInstance.StorageType) {
+				continue
+			}
+			if !candidateFits(currentSpec, spec, profile.P95CPUUtilization, requiredMemoryGiB, config) {
+				continue
+			}
+			if best == "" || spec.memoryGiB < bestSpec.memoryGiB {
+				best = class
+				bestSpec = spec
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// estimateDownsizeSavings is the difference in monthly compute cost between
+// dbInstance's current class and recommendedClass; storage cost is
+// unaffected by a downsize so it is not included.
+func estimateDownsizeSavings(ctx context.Context, pricer pricing.Pricer, region string, dbInstance rdsTypes.DBInstance, recommendedClass string) (int, error) {
+	current, err := pricer.RDSInstancePrice(ctx, pricing.RDSInstancePriceInput{
+		Region:        region,
+		InstanceClass: aws.ToString(dbInstance.DBInstanceClass),
+		Engine:        aws.ToString(dbInstance.Engine),
+		MultiAZ:       aws.ToBool(dbInstance.MultiAZ),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	recommended, err := pricer.RDSInstancePrice(ctx, pricing.RDSInstancePriceInput{
+		Region:        region,
+		InstanceClass: recommendedClass,
+		Engine:        aws.ToString(dbInstance.Engine),
+		MultiAZ:       aws.ToBool(dbInstance.MultiAZ),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	delta := (current - recommended) * hoursPerMonth
+	if delta < 0 {
+		return 0, nil
+	}
+	return int(delta), nil
+}
@@ -0,0 +1,136 @@
+package cost
+
+import "testing"
+
+func TestClassifyUtilization(t *testing.T) {
+	config := DefaultRightsizeDBInstancesConfig()
+	totalMemoryGiB := 16.0 // e.g. db.r5.large
+
+	tests := []struct {
+		name                   string
+		connectionFound        bool
+		knownSpec              bool
+		p95CPUUtilization      float64
+		minFreeableMemoryBytes float64
+		want                   UtilizationClass
+	}{
+		{
+			name:            "no recent connections is idle regardless of utilization",
+			connectionFound: false,
+			knownSpec:       true,
+			want:            UtilizationIdle,
+		},
+		{
+			name:                   "low CPU and lots of memory headroom is underutilized",
+			connectionFound:        true,
+			knownSpec:              true,
+			p95CPUUtilization:      10,
+			minFreeableMemoryBytes: totalMemoryGiB * bytesPerGiB * 0.9,
+			want:                   UtilizationUnderutilized,
+		},
+		{
+			name:                   "low CPU but little memory headroom at peak usage is right-sized",
+			connectionFound:        true,
+			knownSpec:              true,
+			p95CPUUtilization:      10,
+			minFreeableMemoryBytes: totalMemoryGiB * bytesPerGiB * 0.1,
+			want:                   UtilizationRightSized,
+		},
+		{
+			name:                   "high CPU is right-sized even with memory headroom",
+			connectionFound:        true,
+			knownSpec:              true,
+			p95CPUUtilization:      90,
+			minFreeableMemoryBytes: totalMemoryGiB * bytesPerGiB * 0.9,
+			want:                   UtilizationRightSized,
+		},
+		{
+			name:                   "unknown instance class never recommends a downsize",
+			connectionFound:        true,
+			knownSpec:              false,
+			p95CPUUtilization:      10,
+			minFreeableMemoryBytes: totalMemoryGiB * bytesPerGiB * 0.9,
+			want:                   UtilizationRightSized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyUtilization(tt.connectionFound, tt.knownSpec, tt.p95CPUUtilization, tt.minFreeableMemoryBytes, totalMemoryGiB, config)
+			if got != tt.want {
+				t.Errorf("classifyUtilization() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCandidateFits(t *testing.T) {
+	config := DefaultRightsizeDBInstancesConfig() // UnderutilizedCPUPercent: 40
+
+	// db.r5.4xlarge: 16 vCPU, 128 GiB memory.
+	current := instanceSpec{vCPU: 16, memoryGiB: 128}
+
+	tests := []struct {
+		name              string
+		candidate         instanceSpec
+		p95CPUUtilization float64
+		requiredMemoryGiB float64
+		want              bool
+	}{
+		{
+			name:              "smaller class with memory and CPU headroom fits",
+			candidate:         instanceSpec{vCPU: 8, memoryGiB: 64},
+			p95CPUUtilization: 10,
+			requiredMemoryGiB: 32,
+			want:              true,
+		},
+		{
+			name:              "candidate with too little memory does not fit",
+			candidate:         instanceSpec{vCPU: 8, memoryGiB: 16},
+			p95CPUUtilization: 10,
+			requiredMemoryGiB: 32,
+			want:              false,
+		},
+		{
+			name:              "candidate with equal or more vCPUs than current does not fit",
+			candidate:         instanceSpec{vCPU: 16, memoryGiB: 64},
+			p95CPUUtilization: 10,
+			requiredMemoryGiB: 32,
+			want:              false,
+		},
+		{
+			name: "dropping vCPUs far enough to exceed the CPU threshold does not fit",
+			// 39% p95 CPU on 16 vCPU scales to ~312% on 2 vCPU.
+			candidate:         instanceSpec{vCPU: 2, memoryGiB: 64},
+			p95CPUUtilization: 39,
+			requiredMemoryGiB: 32,
+			want:              false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := candidateFits(current, tt.candidate, tt.p95CPUUtilization, tt.requiredMemoryGiB, config)
+			if got != tt.want {
+				t.Errorf("candidateFits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinMaxOfValues(t *testing.T) {
+	if got := maxOfValues(nil); got != 0 {
+		t.Errorf("maxOfValues(nil) = %v, want 0", got)
+	}
+	if got := minOfValues(nil); got != 0 {
+		t.Errorf("minOfValues(nil) = %v, want 0", got)
+	}
+
+	values := []float64{3, 1, 4, 1, 5, 9, 2, 6}
+	if got := maxOfValues(values); got != 9 {
+		t.Errorf("maxOfValues(%v) = %v, want 9", values, got)
+	}
+	if got := minOfValues(values); got != 1 {
+		t.Errorf("minOfValues(%v) = %v, want 1", values, got)
+	}
+}
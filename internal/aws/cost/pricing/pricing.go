@@ -0,0 +1,260 @@
+// Package pricing looks up on-demand AWS pricing for the resources the cost
+// checks reason about, so EstimatedMonthlySavings reflects real dollar
+// amounts instead of being left at zero.
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	pricingTypes "github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+// Pricer is implemented by anything that can price RDS resources. It is
+// exposed on client.AWSClient so cost checks beyond RDS (idle load
+// balancers, unused EBS volumes, etc.) can share the same lookups and cache.
+type Pricer interface {
+	RDSInstancePrice(ctx context.Context, input RDSInstancePriceInput) (float64, error)
+	RDSStoragePrice(ctx context.Context, input RDSStoragePriceInput) (float64, error)
+	RDSIOPSPrice(ctx context.Context, input RDSIOPSPriceInput) (float64, error)
+}
+
+// RDSInstancePriceInput identifies a single hourly on-demand RDS instance rate.
+type RDSInstancePriceInput struct {
+	Region        string
+	InstanceClass string
+	Engine        string
+	MultiAZ       bool
+}
+
+// RDSStoragePriceInput identifies a per-GB-month RDS storage rate.
+type RDSStoragePriceInput struct {
+	Region      string
+	StorageType string // gp2, io1, standard, or aurora
+	// Engine is required when StorageType is "aurora": Aurora storage is
+	// priced per database engine rather than by EBS volumeType, since it
+	// isn't backed by a provisioned EBS volume.
+	Engine string
+}
+
+// RDSIOPSPriceInput identifies a per-IOPS-month RDS provisioned IOPS rate.
+type RDSIOPSPriceInput struct {
+	Region string
+}
+
+// RDSPricer implements Pricer using the AWS Pricing API, caching lookups
+// since identical instance classes and storage types are common across an
+// account.
+type RDSPricer struct {
+	client *pricing.Client
+
+	mu    sync.Mutex
+	cache map[string]float64
+}
+
+// NewRDSPricer returns a Pricer backed by the given Pricing API client.
+func NewRDSPricer(client *pricing.Client) *RDSPricer {
+	return &RDSPricer{
+		client: client,
+		cache:  make(map[string]float64),
+	}
+}
+
+func (p *RDSPricer) RDSInstancePrice(ctx context.Context, input RDSInstancePriceInput) (float64, error) {
+	deployment := "Single-AZ"
+	if input.MultiAZ {
+		deployment = "Multi-AZ"
+	}
+
+	key := fmt.Sprintf("instance|%s|%s|%s|%s", input.Region, input.InstanceClass, input.Engine, deployment)
+
+	if price, ok := p.cached(key); ok {
+		return price, nil
+	}
+
+	price, err := p.lookup(ctx, "AmazonRDS", []pricingTypes.Filter{
+		termMatch("instanceType", input.InstanceClass),
+		termMatch("databaseEngine", pricingEngineName(input.Engine)),
+		termMatch("deploymentOption", deployment),
+		termMatch("termType", "OnDemand"),
+		termMatch("regionCode", input.Region),
+		termMatch("purchaseOption", "No Upfront"),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	p.store(key, price)
+	return price, nil
+}
+
+func (p *RDSPricer) RDSStoragePrice(ctx context.Context, input RDSStoragePriceInput) (float64, error) {
+	key := fmt.Sprintf("storage|%s|%s|%s", input.Region, input.StorageType, input.Engine)
+
+	if price, ok := p.cached(key); ok {
+		return price, nil
+	}
+
+	filters := []pricingTypes.Filter{
+		termMatch("termType", "OnDemand"),
+		termMatch("regionCode", input.Region),
+		termMatch("productFamily", "Database Storage"),
+	}
+
+	// Aurora storage isn't a provisioned EBS volume, so it has no volumeType
+	// in the Pricing API - it's priced per database engine instead.
+	if input.StorageType == "aurora" {
+		filters = append(filters, termMatch("databaseEngine", pricingEngineName(input.Engine)))
+	} else {
+		filters = append(filters, termMatch("volumeType", storageVolumeType(input.StorageType)))
+	}
+
+	price, err := p.lookup(ctx, "AmazonRDS", filters)
+	if err != nil {
+		return 0, err
+	}
+
+	p.store(key, price)
+	return price, nil
+}
+
+func (p *RDSPricer) RDSIOPSPrice(ctx context.Context, input RDSIOPSPriceInput) (float64, error) {
+	key := fmt.Sprintf("iops|%s", input.Region)
+
+	if price, ok := p.cached(key); ok {
+		return price, nil
+	}
+
+	price, err := p.lookup(ctx, "AmazonRDS", []pricingTypes.Filter{
+		termMatch("termType", "OnDemand"),
+		termMatch("regionCode", input.Region),
+		termMatch("productFamily", "Provisioned IOPS"),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	p.store(key, price)
+	return price, nil
+}
+
+func (p *RDSPricer) cached(key string) (float64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	price, ok := p.cache[key]
+	return price, ok
+}
+
+func (p *RDSPricer) store(key string, price float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache[key] = price
+}
+
+func termMatch(field, value string) pricingTypes.Filter {
+	return pricingTypes.Filter{
+		Field: aws.String(field),
+		Type:  pricingTypes.FilterTypeTermMatch,
+		Value: aws.String(value),
+	}
+}
+
+// pricingEngineName maps an RDS engine id, as returned by
+// DescribeDBInstances/DescribeDBClusters (e.g. "postgres", "aurora-mysql",
+// "sqlserver-ee"), to the display name the Pricing API's AmazonRDS
+// databaseEngine attribute expects. TERM_MATCH is exact and case-sensitive,
+// so passing the engine id straight through never matches a real product.
+func pricingEngineName(engine string) string {
+	switch engine {
+	case "aurora", "aurora-mysql":
+		return "Aurora MySQL"
+	case "aurora-postgresql":
+		return "Aurora PostgreSQL"
+	case "mysql":
+		return "MySQL"
+	case "postgres":
+		return "PostgreSQL"
+	case "mariadb":
+		return "MariaDB"
+	case "oracle-ee", "oracle-ee-cdb":
+		return "Oracle"
+	case "oracle-se2", "oracle-se2-cdb":
+		return "Oracle"
+	case "sqlserver-ee", "sqlserver-se", "sqlserver-ex", "sqlserver-web":
+		return "SQL Server"
+	default:
+		return engine
+	}
+}
+
+// storageVolumeType maps an RDS storage type to the Pricing API's
+// volumeType attribute. It does not handle "aurora" - Aurora storage has no
+// volumeType and is priced per database engine instead (see RDSStoragePrice).
+func storageVolumeType(storageType string) string {
+	switch storageType {
+	case "io1":
+		return "Provisioned IOPS"
+	case "standard":
+		return "Magnetic"
+	default:
+		return "General Purpose"
+	}
+}
+
+// priceListProduct mirrors the subset of the Pricing API's PriceList JSON
+// documents needed to pull out the on-demand hourly/unit rate.
+type priceListProduct struct {
+	Terms struct {
+		OnDemand map[string]struct {
+			PriceDimensions map[string]struct {
+				PricePerUnit struct {
+					USD string `json:"USD"`
+				} `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+// lookup queries the Pricing API and returns the first non-zero on-demand
+// rate it finds. A product with no matching price is not an error - the
+// caller leaves the related savings estimate at zero.
+func (p *RDSPricer) lookup(ctx context.Context, serviceCode string, filters []pricingTypes.Filter) (float64, error) {
+	out, err := p.client.GetProducts(ctx, &pricing.GetProductsInput{
+		ServiceCode: aws.String(serviceCode),
+		Filters:     filters,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return parsePriceList(out.PriceList), nil
+}
+
+// parsePriceList pulls the first non-zero on-demand rate out of a Pricing
+// API PriceList. It is split out from lookup so the JSON shape can be unit
+// tested without a live Pricing API client.
+func parsePriceList(priceList []string) float64 {
+	for _, raw := range priceList {
+		var product priceListProduct
+		if err := json.Unmarshal([]byte(raw), &product); err != nil {
+			continue
+		}
+
+		for _, term := range product.Terms.OnDemand {
+			for _, dimension := range term.PriceDimensions {
+				price, err := strconv.ParseFloat(dimension.PricePerUnit.USD, 64)
+				if err != nil || price == 0 {
+					continue
+				}
+				return price
+			}
+		}
+	}
+
+	return 0
+}
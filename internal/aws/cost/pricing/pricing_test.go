@@ -0,0 +1,89 @@
+package pricing
+
+import "testing"
+
+func TestParsePriceList(t *testing.T) {
+	tests := []struct {
+		name      string
+		priceList []string
+		want      float64
+	}{
+		{
+			name:      "no products",
+			priceList: nil,
+			want:      0,
+		},
+		{
+			name: "single on-demand dimension",
+			priceList: []string{
+				`{"terms":{"OnDemand":{"ABC.JRTCKXETXF":{"priceDimensions":{"ABC.JRTCKXETXF.6YS6EN2CT7":{"pricePerUnit":{"USD":"0.0520000000"}}}}}}}`,
+			},
+			want: 0.052,
+		},
+		{
+			name: "zero-priced dimension is skipped in favor of a later non-zero one",
+			priceList: []string{
+				`{"terms":{"OnDemand":{"ABC":{"priceDimensions":{"ABC.0":{"pricePerUnit":{"USD":"0.0000000000"}}}}}}}`,
+				`{"terms":{"OnDemand":{"DEF":{"priceDimensions":{"DEF.0":{"pricePerUnit":{"USD":"0.1740000000"}}}}}}}`,
+			},
+			want: 0.174,
+		},
+		{
+			name:      "malformed product is skipped",
+			priceList: []string{`not json`},
+			want:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePriceList(tt.priceList); got != tt.want {
+				t.Errorf("parsePriceList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPricingEngineName(t *testing.T) {
+	tests := []struct {
+		engine string
+		want   string
+	}{
+		{"postgres", "PostgreSQL"},
+		{"aurora-mysql", "Aurora MySQL"},
+		{"aurora-postgresql", "Aurora PostgreSQL"},
+		{"aurora", "Aurora MySQL"},
+		{"mysql", "MySQL"},
+		{"mariadb", "MariaDB"},
+		{"oracle-ee", "Oracle"},
+		{"sqlserver-ee", "SQL Server"},
+		{"some-future-engine", "some-future-engine"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engine, func(t *testing.T) {
+			if got := pricingEngineName(tt.engine); got != tt.want {
+				t.Errorf("pricingEngineName(%q) = %q, want %q", tt.engine, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorageVolumeType(t *testing.T) {
+	tests := []struct {
+		storageType string
+		want        string
+	}{
+		{"io1", "Provisioned IOPS"},
+		{"standard", "Magnetic"},
+		{"gp2", "General Purpose"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.storageType, func(t *testing.T) {
+			if got := storageVolumeType(tt.storageType); got != tt.want {
+				t.Errorf("storageVolumeType(%q) = %q, want %q", tt.storageType, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+// Package client bundles the AWS SDK clients a check needs to evaluate a
+// single region/account.
+package client
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+
+	"github.com/brittandeyoung/ckia/internal/aws/cost/pricing"
+)
+
+// AWSClient holds the per-region service clients checks are run against.
+type AWSClient struct {
+	Region     string
+	RDS        *rds.Client
+	Cloudwatch *cloudwatch.Client
+
+	// Pricer resolves on-demand pricing for checks that estimate savings.
+	// It is shared across checks so identical lookups are cached once.
+	Pricer pricing.Pricer
+}
@@ -0,0 +1,19 @@
+// Package common holds the types shared across all ckia checks, regardless
+// of which AWS service or category (cost, security, ...) they belong to.
+package common
+
+// Check holds the static metadata shared by every check, along with the
+// configuration it was run with so a report can show how the result was
+// produced.
+type Check struct {
+	Id                  string `json:"id"`
+	Name                string `json:"name"`
+	Description         string `json:"description"`
+	Criteria            string `json:"criteria"`
+	RecommendedAction   string `json:"recommendedAction"`
+	AdditionalResources string `json:"additionalResources"`
+
+	// Config is the check-specific configuration (e.g. IdleDBInstancesConfig)
+	// the check was run with. It is omitted when a check has no configuration.
+	Config any `json:"config,omitempty"`
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/brittandeyoung/ckia/internal/client"
+)
+
+// RegionalCheck runs a check against a single region's AWSClient and
+// returns the rows it found there. Every check's Run method can be adapted
+// to this shape with a small closure, which is what lets MultiRegionRunner
+// fan any of them out across regions.
+type RegionalCheck[T any] func(ctx context.Context, conn client.AWSClient) ([]T, error)
+
+// ClientFactory builds the AWSClient MultiRegionRunner uses to evaluate a
+// single region.
+type ClientFactory func(ctx context.Context, region string) (client.AWSClient, error)
+
+// MultiRegionRunnerConfig controls how a RegionalCheck is fanned out across
+// regions.
+type MultiRegionRunnerConfig struct {
+	Regions []string
+	// Concurrency bounds how many regions are scanned at once. Defaults to 4.
+	Concurrency int
+	// PerRegionTimeout bounds how long a single region's check may run.
+	// Zero means no per-region timeout.
+	PerRegionTimeout time.Duration
+}
+
+// MultiRegionRunner runs check once per region in cfg.Regions, merging the
+// rows every region returns into a single slice. Errors from individual
+// regions are aggregated with errors.Join rather than aborting the sweep, so
+// one bad region does not prevent reporting on the rest.
+func MultiRegionRunner[T any](ctx context.Context, cfg MultiRegionRunnerConfig, newClient ClientFactory, check RegionalCheck[T]) ([]T, error) {
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	type regionResult struct {
+		rows []T
+		err  error
+	}
+
+	results := make([]regionResult, len(cfg.Regions))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, region := range cfg.Regions {
+		i, region := i, region
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			regionCtx := ctx
+			if cfg.PerRegionTimeout > 0 {
+				var cancel context.CancelFunc
+				regionCtx, cancel = context.WithTimeout(ctx, cfg.PerRegionTimeout)
+				defer cancel()
+			}
+
+			conn, err := newClient(regionCtx, region)
+			if err != nil {
+				results[i] = regionResult{err: fmt.Errorf("%s: %w", region, err)}
+				return
+			}
+
+			rows, err := check(regionCtx, conn)
+			if err != nil {
+				results[i] = regionResult{err: fmt.Errorf("%s: %w", region, err)}
+				return
+			}
+
+			results[i] = regionResult{rows: rows}
+		}()
+	}
+
+	wg.Wait()
+
+	var merged []T
+	var errs []error
+	for _, result := range results {
+		if result.err != nil {
+			errs = append(errs, result.err)
+			continue
+		}
+		merged = append(merged, result.rows...)
+	}
+
+	return merged, errors.Join(errs...)
+}
+
+// DiscoverRegions lists the regions enabled for the caller's account, for
+// callers that want MultiRegionRunner to scan an entire org-wide account
+// rather than an explicit region list.
+func DiscoverRegions(ctx context.Context, ec2Client *ec2.Client) ([]string, error) {
+	output, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{
+		AllRegions: aws.Bool(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	regions := make([]string, 0, len(output.Regions))
+	for _, region := range output.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
+	}
+	return regions, nil
+}
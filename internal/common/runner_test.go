@@ -0,0 +1,97 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/brittandeyoung/ckia/internal/client"
+)
+
+func newClientForRegion(ctx context.Context, region string) (client.AWSClient, error) {
+	return client.AWSClient{Region: region}, nil
+}
+
+func TestMultiRegionRunnerMergesRows(t *testing.T) {
+	cfg := MultiRegionRunnerConfig{
+		Regions:     []string{"us-east-1", "us-west-2", "eu-west-1"},
+		Concurrency: 2,
+	}
+
+	rows, err := MultiRegionRunner(context.Background(), cfg, newClientForRegion, func(ctx context.Context, conn client.AWSClient) ([]string, error) {
+		return []string{conn.Region + "-row"}, nil
+	})
+	if err != nil {
+		t.Fatalf("MultiRegionRunner() error = %v", err)
+	}
+
+	sort.Strings(rows)
+	want := []string{"eu-west-1-row", "us-east-1-row", "us-west-2-row"}
+	if len(rows) != len(want) {
+		t.Fatalf("MultiRegionRunner() rows = %v, want %v", rows, want)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("MultiRegionRunner() rows = %v, want %v", rows, want)
+			break
+		}
+	}
+}
+
+func TestMultiRegionRunnerAggregatesErrorsWithoutAbortingOtherRegions(t *testing.T) {
+	cfg := MultiRegionRunnerConfig{
+		Regions:     []string{"us-east-1", "us-west-2", "eu-west-1"},
+		Concurrency: 2,
+	}
+
+	boom := errors.New("boom")
+
+	rows, err := MultiRegionRunner(context.Background(), cfg, newClientForRegion, func(ctx context.Context, conn client.AWSClient) ([]string, error) {
+		if conn.Region == "us-west-2" {
+			return nil, boom
+		}
+		return []string{conn.Region + "-row"}, nil
+	})
+
+	if err == nil {
+		t.Fatal("MultiRegionRunner() error = nil, want a joined error naming the failing region")
+	}
+	if !errors.Is(err, boom) {
+		t.Errorf("MultiRegionRunner() error = %v, want it to wrap %v", err, boom)
+	}
+	wantMsg := fmt.Sprintf("us-west-2: %v", boom)
+	found := false
+	for _, e := range unwrapJoined(err) {
+		if e.Error() == wantMsg {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("MultiRegionRunner() error = %v, want it to contain %q", err, wantMsg)
+	}
+
+	sort.Strings(rows)
+	want := []string{"eu-west-1-row", "us-east-1-row"}
+	if len(rows) != len(want) {
+		t.Fatalf("MultiRegionRunner() rows = %v, want %v (the good regions should still report)", rows, want)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("MultiRegionRunner() rows = %v, want %v", rows, want)
+			break
+		}
+	}
+}
+
+// unwrapJoined pulls the individual errors out of an errors.Join tree.
+func unwrapJoined(err error) []error {
+	type multiError interface {
+		Unwrap() []error
+	}
+	if joined, ok := err.(multiError); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}